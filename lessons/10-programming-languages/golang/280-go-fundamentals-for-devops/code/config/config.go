@@ -12,6 +12,7 @@ type AppConfig struct {
     Debug       bool
     Timeout     time.Duration
     LogLevel    string
+    DBPath      string
 }
 
 func Load() *AppConfig {
@@ -21,6 +22,7 @@ func Load() *AppConfig {
         Debug:       getEnvAsBool("DEBUG", false),
         Timeout:     getEnvAsDuration("TIMEOUT", 30*time.Second),
         LogLevel:    getEnv("LOG_LEVEL", "info"),
+        DBPath:      getEnv("DB_PATH", "monitor.db"),
     }
 }
 
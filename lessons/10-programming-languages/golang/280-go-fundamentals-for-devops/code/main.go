@@ -2,59 +2,257 @@ package main
 
 import (
     "context"
+    "errors"
     "fmt"
-    "strings"
+    "log/slog"
+    "net/http"
+    "os"
+    "os/signal"
+    "sync/atomic"
+    "syscall"
     "time"
-    
+
     "devops-tutorial/config"
     "devops-tutorial/monitor"
+    "devops-tutorial/storage"
+
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/promauto"
+    "github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const historyWindow = 24 * time.Hour
+
+const checkInterval = 30 * time.Second
+
+var (
+    healthcheckUp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+        Name: "healthcheck_up",
+        Help: "Whether the last check of an endpoint succeeded (1) or not (0).",
+    }, []string{"endpoint"})
+
+    healthcheckDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+        Name: "healthcheck_duration_seconds",
+        Help: "Duration of health checks, in seconds.",
+    }, []string{"endpoint"})
+
+    healthcheckFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+        Name: "healthcheck_failures_total",
+        Help: "Total number of failed health checks.",
+    }, []string{"endpoint"})
 )
 
 func main() {
-    // Load configuration
     cfg := config.Load()
-    
-    fmt.Printf("🚀 DevOps Health Monitor Starting...\n")
-    fmt.Printf("Environment: %s\n", cfg.Environment)
-    fmt.Printf("Debug Mode: %t\n", cfg.Debug)
-    fmt.Printf("Timeout: %v\n", cfg.Timeout)
-    fmt.Printf("Log Level: %s\n", cfg.LogLevel)
-    
-    // Create health checker
-    healthChecker := monitor.NewHealthChecker(cfg.Timeout)
-    
-    // Example endpoints to monitor
-    endpoints := []string{
-        "https://httpbin.org/status/200",
-        "https://httpbin.org/status/500",
-        "https://httpbin.org/delay/1",
-        "https://invalid-url-that-does-not-exist.com",
-    }
-    
-    fmt.Println("\n🔍 Running health checks...")
-    
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
-    
-    statuses := healthChecker.CheckMultiple(ctx, endpoints)
-    
-    fmt.Println("\n📊 Health Check Results:")
-    fmt.Println(strings.Repeat("=", 50))
-    
-    for _, status := range statuses {
-        emoji := "✅"
-        if status.Error != nil {
-            emoji = "❌"
+
+    logger := newLogger(cfg.LogLevel)
+    slog.SetDefault(logger)
+
+    store, err := storage.NewSQLiteStore(cfg.DBPath)
+    if err != nil {
+        logger.Error("failed to open history store", "error", err)
+        os.Exit(1)
+    }
+    defer store.Close()
+
+    if len(os.Args) > 1 && os.Args[1] == "history" {
+        runHistory(store, logger)
+        return
+    }
+
+    logger.Info("starting devops health monitor",
+        "environment", cfg.Environment,
+        "debug", cfg.Debug,
+        "port", cfg.Port,
+    )
+
+    endpoints := []monitor.EndpointConfig{
+        {Endpoint: "https://httpbin.org/status/200"},
+        {Endpoint: "https://httpbin.org/status/500"},
+        {Endpoint: "https://httpbin.org/delay/1"},
+        {Endpoint: "https://invalid-url-that-does-not-exist.com"},
+        {
+            Endpoint: "https://geth.example.com",
+            Checker:  monitor.GethChecker{MaxBlocksBehind: 10, MaxTimeSinceLatestBlock: 2 * time.Minute},
+        },
+        {
+            Endpoint: "https://beacon.example.com",
+            Checker:  monitor.LighthouseChecker{MaxSyncDistance: 10},
+        },
+    }
+    healthChecker := monitor.NewHealthChecker(cfg.Timeout, endpoints)
+
+    var ready atomic.Bool
+
+    mux := http.NewServeMux()
+    mux.Handle("/metrics", promhttp.Handler())
+    mux.HandleFunc("/livez", func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(http.StatusOK)
+        fmt.Fprintln(w, "ok")
+    })
+    mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+        if !ready.Load() {
+            w.WriteHeader(http.StatusServiceUnavailable)
+            fmt.Fprintln(w, "not ready")
+            return
         }
-        
-        fmt.Printf("%s %s\n", emoji, status.Endpoint)
-        fmt.Printf("   Status: %s\n", status.Status)
-        fmt.Printf("   Duration: %v\n", status.Duration)
-        if status.Error != nil {
-            fmt.Printf("   Error: %v\n", status.Error)
+        w.WriteHeader(http.StatusOK)
+        fmt.Fprintln(w, "ok")
+    })
+
+    server := &http.Server{
+        Addr:         fmt.Sprintf(":%d", cfg.Port),
+        Handler:      mux,
+        ReadTimeout:  5 * time.Second,
+        WriteTimeout: 10 * time.Second,
+        IdleTimeout:  60 * time.Second,
+    }
+
+    go func() {
+        logger.Info("metrics server listening", "addr", server.Addr)
+        if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+            logger.Error("metrics server failed", "error", err)
+        }
+    }()
+
+    runChecks := func(ctx context.Context) {
+        checkCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+        defer cancel()
+
+        previous, err := store.LastHealthByEndpoint(checkCtx)
+        if err != nil {
+            logger.Error("failed to load previous health snapshot", "error", err)
         }
-        fmt.Println()
+
+        statuses, err := healthChecker.CheckMultiple(checkCtx)
+        if err != nil {
+            logger.Error("health check batch did not finish", "error", err)
+        }
+
+        for _, status := range statuses {
+            recordMetrics(status)
+            recordHistory(checkCtx, store, logger, status)
+            logRegression(logger, previous[status.Endpoint], status)
+
+            if status.Error != nil {
+                logger.Warn("endpoint unhealthy",
+                    "endpoint", status.Endpoint,
+                    "status", status.Status,
+                    "error", status.Error,
+                    "details", status.Details,
+                )
+                continue
+            }
+            logger.Info("endpoint healthy",
+                "endpoint", status.Endpoint,
+                "duration", status.Duration,
+                "details", status.Details,
+            )
+        }
+
+        ready.Store(true)
+    }
+
+    ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+    defer stop()
+
+    runChecks(ctx)
+
+    ticker := time.NewTicker(checkInterval)
+    defer ticker.Stop()
+
+loop:
+    for {
+        select {
+        case <-ticker.C:
+            runChecks(ctx)
+        case <-ctx.Done():
+            break loop
+        }
+    }
+
+    logger.Info("shutdown signal received, draining connections")
+    shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+    defer cancel()
+
+    if err := server.Shutdown(shutdownCtx); err != nil {
+        logger.Error("graceful shutdown failed", "error", err)
+        os.Exit(1)
+    }
+    logger.Info("shutdown complete")
+}
+
+func recordHistory(ctx context.Context, store storage.Store, logger *slog.Logger, status monitor.HealthStatus) {
+    errText := ""
+    if status.Error != nil {
+        errText = status.Error.Error()
+    }
+
+    rec := storage.HealthRecord{
+        Endpoint:  status.Endpoint,
+        Timestamp: time.Now(),
+        Status:    status.Status,
+        LatencyMS: status.Duration.Milliseconds(),
+        Error:     errText,
+    }
+    if err := store.RecordHealth(ctx, rec); err != nil {
+        logger.Error("failed to persist health snapshot", "endpoint", status.Endpoint, "error", err)
+    }
+}
+
+// logRegression compares this check against the last recorded one for the
+// same endpoint and flags transitions into and out of a healthy state.
+// prev.Status is empty when there's no prior history, e.g. the endpoint's
+// first check.
+func logRegression(logger *slog.Logger, prev storage.HealthRecord, current monitor.HealthStatus) {
+    if prev.Status == "" {
+        return
+    }
+    wasHealthy := prev.Status == "up"
+    isHealthy := current.Status == "up"
+
+    switch {
+    case wasHealthy && !isHealthy:
+        logger.Warn("endpoint newly unhealthy", "endpoint", current.Endpoint, "previous_status", prev.Status, "status", current.Status)
+    case !wasHealthy && isHealthy:
+        logger.Info("endpoint recovered", "endpoint", current.Endpoint, "previous_status", prev.Status)
+    }
+}
+
+// runHistory prints per-endpoint uptime over historyWindow and exits; it
+// is invoked via `history` as the first CLI argument instead of starting
+// the long-running service.
+func runHistory(store storage.Store, logger *slog.Logger) {
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+    defer cancel()
+
+    uptime, err := store.UptimeSince(ctx, time.Now().Add(-historyWindow))
+    if err != nil {
+        logger.Error("failed to compute uptime history", "error", err)
+        os.Exit(1)
+    }
+
+    fmt.Printf("Uptime over the last %s:\n", historyWindow)
+    for endpoint, pct := range uptime {
+        fmt.Printf("  %-50s %.2f%%\n", endpoint, pct)
+    }
+}
+
+func recordMetrics(status monitor.HealthStatus) {
+    up := 0.0
+    if status.Error == nil {
+        up = 1.0
+    } else {
+        healthcheckFailures.WithLabelValues(status.Endpoint).Inc()
+    }
+    healthcheckUp.WithLabelValues(status.Endpoint).Set(up)
+    healthcheckDuration.WithLabelValues(status.Endpoint).Observe(status.Duration.Seconds())
+}
+
+func newLogger(level string) *slog.Logger {
+    var lvl slog.Level
+    if err := lvl.UnmarshalText([]byte(level)); err != nil {
+        lvl = slog.LevelInfo
     }
-    
-    fmt.Println("✨ Health check completed!")
+    return slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: lvl}))
 }
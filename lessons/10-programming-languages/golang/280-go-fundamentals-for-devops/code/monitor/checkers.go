@@ -0,0 +1,234 @@
+package monitor
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// rpcRequest/rpcResponse model the subset of JSON-RPC used by the
+// node-specific checkers below.
+type rpcRequest struct {
+    JSONRPC string `json:"jsonrpc"`
+    Method  string `json:"method"`
+    Params  []any  `json:"params"`
+    ID      int    `json:"id"`
+}
+
+type rpcResponse struct {
+    Result json.RawMessage `json:"result"`
+    Error  *struct {
+        Code    int    `json:"code"`
+        Message string `json:"message"`
+    } `json:"error"`
+}
+
+func callRPC(ctx context.Context, client *http.Client, endpoint, method string, params []any) (json.RawMessage, error) {
+    body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", Method: method, Params: params, ID: 1})
+    if err != nil {
+        return nil, fmt.Errorf("encode %s request: %w", method, err)
+    }
+
+    req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+    if err != nil {
+        return nil, fmt.Errorf("build %s request: %w", method, err)
+    }
+    req.Header.Set("Content-Type", "application/json")
+
+    resp, err := client.Do(req)
+    if err != nil {
+        return nil, fmt.Errorf("call %s: %w", method, err)
+    }
+    defer resp.Body.Close()
+
+    var rpcResp rpcResponse
+    if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+        return nil, fmt.Errorf("decode %s response: %w", method, err)
+    }
+    if rpcResp.Error != nil {
+        return nil, fmt.Errorf("%s rpc error %d: %s", method, rpcResp.Error.Code, rpcResp.Error.Message)
+    }
+
+    return rpcResp.Result, nil
+}
+
+func hexToUint64(hex string) (uint64, error) {
+    return strconv.ParseUint(strings.TrimPrefix(hex, "0x"), 16, 64)
+}
+
+// executionSyncStatus is the shape shared by eth_syncing results across
+// Geth, Reth and OP-node.
+type executionSyncStatus struct {
+    CurrentBlock string `json:"currentBlock"`
+    HighestBlock string `json:"highestBlock"`
+}
+
+// checkExecutionClient drives the eth_syncing / eth_blockNumber pair used
+// by Geth, Reth and OP-node, and applies the shared thresholds. name is
+// used only to label errors (e.g. "geth", "reth", "op-node").
+func checkExecutionClient(ctx context.Context, client *http.Client, endpoint, name string, maxBlocksBehind uint64, maxTimeSinceLatestBlock time.Duration) HealthStatus {
+    start := time.Now()
+    details := map[string]any{}
+
+    syncingRaw, err := callRPC(ctx, client, endpoint, "eth_syncing", nil)
+    if err != nil {
+        return HealthStatus{Endpoint: endpoint, Status: "down", Error: fmt.Errorf("%s: %w", name, err), Duration: time.Since(start)}
+    }
+
+    var syncing bool
+    var syncStatus executionSyncStatus
+    if err := json.Unmarshal(syncingRaw, &syncing); err != nil {
+        // Not a bool, so it must be the in-progress sync object.
+        if err := json.Unmarshal(syncingRaw, &syncStatus); err != nil {
+            return HealthStatus{Endpoint: endpoint, Status: "error", Error: fmt.Errorf("%s: unexpected eth_syncing result: %w", name, err), Duration: time.Since(start)}
+        }
+        syncing = true
+    }
+    details["syncing"] = syncing
+
+    blockNumberRaw, err := callRPC(ctx, client, endpoint, "eth_blockNumber", nil)
+    if err != nil {
+        return HealthStatus{Endpoint: endpoint, Status: "down", Error: fmt.Errorf("%s: %w", name, err), Duration: time.Since(start), Details: details}
+    }
+    var blockNumberHex string
+    if err := json.Unmarshal(blockNumberRaw, &blockNumberHex); err != nil {
+        return HealthStatus{Endpoint: endpoint, Status: "error", Error: fmt.Errorf("%s: decode eth_blockNumber: %w", name, err), Duration: time.Since(start), Details: details}
+    }
+    blockHeight, err := hexToUint64(blockNumberHex)
+    if err != nil {
+        return HealthStatus{Endpoint: endpoint, Status: "error", Error: fmt.Errorf("%s: parse block number: %w", name, err), Duration: time.Since(start), Details: details}
+    }
+    details["blockHeight"] = blockHeight
+
+    blockRaw, err := callRPC(ctx, client, endpoint, "eth_getBlockByNumber", []any{"latest", false})
+    if err == nil {
+        var block struct {
+            Timestamp string `json:"timestamp"`
+        }
+        if err := json.Unmarshal(blockRaw, &block); err == nil {
+            if ts, err := hexToUint64(block.Timestamp); err == nil {
+                sinceLatest := time.Since(time.Unix(int64(ts), 0))
+                details["timeSinceLatestBlock"] = sinceLatest.String()
+                if maxTimeSinceLatestBlock > 0 && sinceLatest > maxTimeSinceLatestBlock {
+                    return HealthStatus{
+                        Endpoint: endpoint,
+                        Status:   "unhealthy",
+                        Error:    fmt.Errorf("%s: latest block is %s old, exceeds max of %s", name, sinceLatest, maxTimeSinceLatestBlock),
+                        Duration: time.Since(start),
+                        Details:  details,
+                    }
+                }
+            }
+        }
+    }
+
+    if syncing {
+        current, errCurrent := hexToUint64(syncStatus.CurrentBlock)
+        highest, errHighest := hexToUint64(syncStatus.HighestBlock)
+        if errCurrent == nil && errHighest == nil && highest > current {
+            behind := highest - current
+            details["blocksBehind"] = behind
+            if maxBlocksBehind > 0 && behind > maxBlocksBehind {
+                return HealthStatus{
+                    Endpoint: endpoint,
+                    Status:   "unhealthy",
+                    Error:    fmt.Errorf("%s: %d blocks behind, exceeds max of %d", name, behind, maxBlocksBehind),
+                    Duration: time.Since(start),
+                    Details:  details,
+                }
+            }
+        }
+    }
+
+    return HealthStatus{Endpoint: endpoint, Status: "up", Duration: time.Since(start), Details: details}
+}
+
+// GethChecker validates a go-ethereum execution client via eth_syncing
+// and eth_blockNumber.
+type GethChecker struct {
+    MaxBlocksBehind         uint64
+    MaxTimeSinceLatestBlock time.Duration
+}
+
+func (c GethChecker) Check(ctx context.Context, client *http.Client, endpoint string) HealthStatus {
+    return checkExecutionClient(ctx, client, endpoint, "geth", c.MaxBlocksBehind, c.MaxTimeSinceLatestBlock)
+}
+
+// RethChecker validates a Reth execution client. Reth speaks the same
+// JSON-RPC surface as Geth for the calls we care about.
+type RethChecker struct {
+    MaxBlocksBehind         uint64
+    MaxTimeSinceLatestBlock time.Duration
+}
+
+func (c RethChecker) Check(ctx context.Context, client *http.Client, endpoint string) HealthStatus {
+    return checkExecutionClient(ctx, client, endpoint, "reth", c.MaxBlocksBehind, c.MaxTimeSinceLatestBlock)
+}
+
+// OpNodeChecker validates an op-node execution endpoint the same way as
+// Geth/Reth; OP Stack nodes expose the standard eth_syncing RPC too.
+type OpNodeChecker struct {
+    MaxBlocksBehind         uint64
+    MaxTimeSinceLatestBlock time.Duration
+}
+
+func (c OpNodeChecker) Check(ctx context.Context, client *http.Client, endpoint string) HealthStatus {
+    return checkExecutionClient(ctx, client, endpoint, "op-node", c.MaxBlocksBehind, c.MaxTimeSinceLatestBlock)
+}
+
+// LighthouseChecker validates a Lighthouse beacon node via the standard
+// /eth/v1/node/syncing beacon API.
+type LighthouseChecker struct {
+    MaxSyncDistance uint64
+}
+
+func (c LighthouseChecker) Check(ctx context.Context, client *http.Client, endpoint string) HealthStatus {
+    start := time.Now()
+
+    url := strings.TrimRight(endpoint, "/") + "/eth/v1/node/syncing"
+    req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+    if err != nil {
+        return HealthStatus{Endpoint: endpoint, Status: "error", Error: fmt.Errorf("lighthouse: %w", err), Duration: time.Since(start)}
+    }
+
+    resp, err := client.Do(req)
+    if err != nil {
+        return HealthStatus{Endpoint: endpoint, Status: "down", Error: fmt.Errorf("lighthouse: %w", err), Duration: time.Since(start)}
+    }
+    defer resp.Body.Close()
+
+    var body struct {
+        Data struct {
+            HeadSlot    string `json:"head_slot"`
+            SyncDistance string `json:"sync_distance"`
+            IsSyncing   bool   `json:"is_syncing"`
+        } `json:"data"`
+    }
+    if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+        return HealthStatus{Endpoint: endpoint, Status: "error", Error: fmt.Errorf("lighthouse: decode syncing response: %w", err), Duration: time.Since(start)}
+    }
+
+    syncDistance, _ := strconv.ParseUint(body.Data.SyncDistance, 10, 64)
+    details := map[string]any{
+        "headSlot":     body.Data.HeadSlot,
+        "syncDistance": syncDistance,
+        "syncing":      body.Data.IsSyncing,
+    }
+
+    if c.MaxSyncDistance > 0 && syncDistance > c.MaxSyncDistance {
+        return HealthStatus{
+            Endpoint: endpoint,
+            Status:   "unhealthy",
+            Error:    fmt.Errorf("lighthouse: sync distance %d exceeds max of %d", syncDistance, c.MaxSyncDistance),
+            Duration: time.Since(start),
+            Details:  details,
+        }
+    }
+
+    return HealthStatus{Endpoint: endpoint, Status: "up", Duration: time.Since(start), Details: details}
+}
@@ -4,12 +4,56 @@ import (
     "context"
     "fmt"
     "net/http"
+    "runtime"
     "time"
+
+    "golang.org/x/sync/errgroup"
 )
 
+// RetryPolicy configures retry-with-backoff for transient network errors
+// (e.g. connection refused, timeout) encountered while checking an
+// endpoint. A zero-value RetryPolicy disables retries.
+type RetryPolicy struct {
+    MaxRetries     int
+    InitialBackoff time.Duration
+    MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy retries a transient failure twice, backing off
+// exponentially starting at 200ms.
+var DefaultRetryPolicy = RetryPolicy{
+    MaxRetries:     2,
+    InitialBackoff: 200 * time.Millisecond,
+    MaxBackoff:     2 * time.Second,
+}
+
+// Checker validates the liveness of a specific kind of endpoint (a plain
+// HTTP service, a Geth node, a beacon node, ...). Implementations issue
+// whatever protocol call is appropriate and decide for themselves what
+// "healthy" means for that service.
+type Checker interface {
+    Check(ctx context.Context, client *http.Client, endpoint string) HealthStatus
+}
+
+// EndpointConfig binds an endpoint to the Checker that knows how to probe
+// it. A nil Checker falls back to a plain HTTP GET.
+type EndpointConfig struct {
+    Endpoint string
+    Checker  Checker
+}
+
 type HealthChecker struct {
-    client  *http.Client
-    timeout time.Duration
+    client    *http.Client
+    timeout   time.Duration
+    endpoints []EndpointConfig
+
+    // MaxConcurrent caps how many endpoints are checked at once. Defaults
+    // to runtime.NumCPU(); non-positive values are treated as unset by
+    // CheckMultiple so a zero-value HealthChecker can't deadlock.
+    MaxConcurrent int
+    // Retry controls retry-with-backoff for transient network errors.
+    // Defaults to DefaultRetryPolicy.
+    Retry RetryPolicy
 }
 
 type HealthStatus struct {
@@ -17,20 +61,31 @@ type HealthStatus struct {
     Status   string
     Error    error
     Duration time.Duration
+    // Details carries checker-specific diagnostics, e.g. block height,
+    // peer count, or sync distance, so callers can show why an endpoint
+    // was reported unhealthy.
+    Details map[string]any
 }
 
-func NewHealthChecker(timeout time.Duration) *HealthChecker {
+// NewHealthChecker builds a checker for the given endpoints. Endpoints
+// without a Checker are probed with a plain HTTP GET.
+func NewHealthChecker(timeout time.Duration, endpoints []EndpointConfig) *HealthChecker {
     return &HealthChecker{
         client: &http.Client{
             Timeout: timeout,
         },
-        timeout: timeout,
+        timeout:       timeout,
+        endpoints:     endpoints,
+        MaxConcurrent: runtime.NumCPU(),
+        Retry:         DefaultRetryPolicy,
     }
 }
 
+// Check performs a plain HTTP GET health check against endpoint. It is
+// also used as the fallback for EndpointConfig entries with no Checker.
 func (hc *HealthChecker) Check(ctx context.Context, endpoint string) HealthStatus {
     start := time.Now()
-    
+
     req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
     if err != nil {
         return HealthStatus{
@@ -40,7 +95,7 @@ func (hc *HealthChecker) Check(ctx context.Context, endpoint string) HealthStatu
             Duration: time.Since(start),
         }
     }
-    
+
     resp, err := hc.client.Do(req)
     if err != nil {
         return HealthStatus{
@@ -51,13 +106,13 @@ func (hc *HealthChecker) Check(ctx context.Context, endpoint string) HealthStatu
         }
     }
     defer resp.Body.Close()
-    
+
     status := "up"
     if resp.StatusCode >= 400 {
         status = "unhealthy"
         err = fmt.Errorf("HTTP %d", resp.StatusCode)
     }
-    
+
     return HealthStatus{
         Endpoint: endpoint,
         Status:   status,
@@ -66,21 +121,68 @@ func (hc *HealthChecker) Check(ctx context.Context, endpoint string) HealthStatu
     }
 }
 
-func (hc *HealthChecker) CheckMultiple(ctx context.Context, endpoints []string) []HealthStatus {
-    results := make(chan HealthStatus, len(endpoints))
-    
-    // Launch goroutines for concurrent checks
-    for _, endpoint := range endpoints {
-        go func(ep string) {
-            results <- hc.Check(ctx, ep)
-        }(endpoint)
+// CheckMultiple runs a check against every configured endpoint, using its
+// EndpointConfig.Checker when one is set and falling back to a plain HTTP
+// GET otherwise. At most MaxConcurrent checks run at a time. If ctx is
+// canceled or its deadline is exceeded before every endpoint has been
+// checked, CheckMultiple returns early with that error; statuses already
+// computed are still returned.
+func (hc *HealthChecker) CheckMultiple(ctx context.Context) ([]HealthStatus, error) {
+    statuses := make([]HealthStatus, len(hc.endpoints))
+
+    limit := hc.MaxConcurrent
+    if limit <= 0 {
+        limit = runtime.NumCPU()
     }
-    
-    // Collect results
-    var statuses []HealthStatus
-    for i := 0; i < len(endpoints); i++ {
-        statuses = append(statuses, <-results)
+
+    g, gctx := errgroup.WithContext(ctx)
+    g.SetLimit(limit)
+
+    for i, ep := range hc.endpoints {
+        i, ep := i, ep
+        g.Go(func() error {
+            if err := gctx.Err(); err != nil {
+                return err
+            }
+            statuses[i] = hc.checkWithRetry(gctx, ep)
+            return nil
+        })
+    }
+
+    if err := g.Wait(); err != nil {
+        return statuses, err
+    }
+    return statuses, nil
+}
+
+// checkWithRetry runs ep's check, retrying transient network failures
+// (Status == "down") according to hc.Retry with exponential backoff.
+// ctx.Done() is honored between attempts so a canceled batch doesn't keep
+// retrying.
+func (hc *HealthChecker) checkWithRetry(ctx context.Context, ep EndpointConfig) HealthStatus {
+    backoff := hc.Retry.InitialBackoff
+
+    for attempt := 0; ; attempt++ {
+        var status HealthStatus
+        if ep.Checker != nil {
+            status = ep.Checker.Check(ctx, hc.client, ep.Endpoint)
+        } else {
+            status = hc.Check(ctx, ep.Endpoint)
+        }
+
+        if status.Status != "down" || attempt >= hc.Retry.MaxRetries {
+            return status
+        }
+
+        select {
+        case <-time.After(backoff):
+        case <-ctx.Done():
+            return status
+        }
+
+        backoff *= 2
+        if backoff > hc.Retry.MaxBackoff {
+            backoff = hc.Retry.MaxBackoff
+        }
     }
-    
-    return statuses
 }
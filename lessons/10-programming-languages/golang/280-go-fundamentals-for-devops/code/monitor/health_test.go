@@ -2,8 +2,10 @@ package monitor
 
 import (
     "context"
+    "encoding/json"
     "net/http"
     "net/http/httptest"
+    "runtime"
     "testing"
     "time"
 )
@@ -15,22 +17,22 @@ func TestHealthChecker_Check(t *testing.T) {
         w.Write([]byte("OK"))
     }))
     defer server.Close()
-    
+
     // Create health checker
-    hc := NewHealthChecker(5 * time.Second)
-    
+    hc := NewHealthChecker(5*time.Second, nil)
+
     // Test successful check
     ctx := context.Background()
     status := hc.Check(ctx, server.URL)
-    
+
     if status.Status != "up" {
         t.Errorf("Expected status 'up', got '%s'", status.Status)
     }
-    
+
     if status.Error != nil {
         t.Errorf("Expected no error, got %v", status.Error)
     }
-    
+
     if status.Duration <= 0 {
         t.Error("Expected positive duration")
     }
@@ -42,27 +44,33 @@ func TestHealthChecker_CheckMultiple(t *testing.T) {
         w.WriteHeader(http.StatusOK)
     }))
     defer server1.Close()
-    
+
     server2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
         w.WriteHeader(http.StatusInternalServerError)
     }))
     defer server2.Close()
-    
-    hc := NewHealthChecker(5 * time.Second)
-    endpoints := []string{server1.URL, server2.URL}
-    
+
+    endpoints := []EndpointConfig{
+        {Endpoint: server1.URL},
+        {Endpoint: server2.URL},
+    }
+    hc := NewHealthChecker(5*time.Second, endpoints)
+
     ctx := context.Background()
-    statuses := hc.CheckMultiple(ctx, endpoints)
-    
+    statuses, err := hc.CheckMultiple(ctx)
+    if err != nil {
+        t.Fatalf("Expected no error, got %v", err)
+    }
+
     if len(statuses) != 2 {
         t.Errorf("Expected 2 statuses, got %d", len(statuses))
     }
-    
+
     // Check first endpoint (should be up)
     if statuses[0].Status != "up" && statuses[1].Status != "up" {
         t.Error("Expected at least one endpoint to be up")
     }
-    
+
     // Check second endpoint (should be unhealthy)
     hasUnhealthy := false
     for _, status := range statuses {
@@ -75,3 +83,107 @@ func TestHealthChecker_CheckMultiple(t *testing.T) {
         t.Error("Expected at least one endpoint to be unhealthy")
     }
 }
+
+func TestGethChecker_Check(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        var req rpcRequest
+        json.NewDecoder(r.Body).Decode(&req)
+
+        var result any
+        switch req.Method {
+        case "eth_syncing":
+            result = false
+        case "eth_blockNumber":
+            result = "0x64"
+        case "eth_getBlockByNumber":
+            result = map[string]any{"timestamp": "0x0"}
+        }
+
+        json.NewEncoder(w).Encode(rpcResponse{Result: mustMarshal(t, result)})
+    }))
+    defer server.Close()
+
+    hc := NewHealthChecker(5*time.Second, []EndpointConfig{
+        {Endpoint: server.URL, Checker: GethChecker{MaxBlocksBehind: 10, MaxTimeSinceLatestBlock: time.Minute}},
+    })
+
+    statuses, err := hc.CheckMultiple(context.Background())
+    if err != nil {
+        t.Fatalf("Expected no error, got %v", err)
+    }
+    if len(statuses) != 1 {
+        t.Fatalf("Expected 1 status, got %d", len(statuses))
+    }
+
+    status := statuses[0]
+    if status.Status != "unhealthy" {
+        t.Errorf("Expected status 'unhealthy' (block from 1970), got '%s': %v", status.Status, status.Error)
+    }
+    if status.Details["blockHeight"] != uint64(100) {
+        t.Errorf("Expected blockHeight 100, got %v", status.Details["blockHeight"])
+    }
+}
+
+func TestLighthouseChecker_Check(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        json.NewEncoder(w).Encode(map[string]any{
+            "data": map[string]any{
+                "head_slot":     "100",
+                "sync_distance": "2",
+                "is_syncing":    false,
+            },
+        })
+    }))
+    defer server.Close()
+
+    hc := NewHealthChecker(5*time.Second, []EndpointConfig{
+        {Endpoint: server.URL, Checker: LighthouseChecker{MaxSyncDistance: 10}},
+    })
+
+    statuses, err := hc.CheckMultiple(context.Background())
+    if err != nil {
+        t.Fatalf("Expected no error, got %v", err)
+    }
+    if len(statuses) != 1 {
+        t.Fatalf("Expected 1 status, got %d", len(statuses))
+    }
+    if statuses[0].Status != "up" {
+        t.Errorf("Expected status 'up', got '%s': %v", statuses[0].Status, statuses[0].Error)
+    }
+}
+
+func TestHealthChecker_CheckMultiple_ContextCanceled(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        time.Sleep(50 * time.Millisecond)
+        w.WriteHeader(http.StatusOK)
+    }))
+    defer server.Close()
+
+    hc := NewHealthChecker(5*time.Second, []EndpointConfig{
+        {Endpoint: server.URL},
+    })
+
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+
+    _, err := hc.CheckMultiple(ctx)
+    if err == nil {
+        t.Error("Expected CheckMultiple to propagate context cancellation")
+    }
+}
+
+func TestHealthChecker_CheckMultiple_MaxConcurrentDefault(t *testing.T) {
+    hc := NewHealthChecker(5*time.Second, nil)
+    if hc.MaxConcurrent != runtime.NumCPU() {
+        t.Errorf("Expected MaxConcurrent to default to runtime.NumCPU() (%d), got %d", runtime.NumCPU(), hc.MaxConcurrent)
+    }
+}
+
+func mustMarshal(t *testing.T, v any) json.RawMessage {
+    t.Helper()
+    b, err := json.Marshal(v)
+    if err != nil {
+        t.Fatalf("marshal: %v", err)
+    }
+    return b
+}
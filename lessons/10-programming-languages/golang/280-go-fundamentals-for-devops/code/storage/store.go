@@ -0,0 +1,147 @@
+// Package storage persists health-check history so the monitor can answer
+// questions that a single run can't: "was this endpoint down an hour ago?",
+// "what's its uptime over the last day?". Store is the seam that keeps the
+// rest of the monitor storage-agnostic; Postgres or any other backend can
+// be swapped in by implementing the same interface.
+package storage
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "time"
+
+    _ "modernc.org/sqlite"
+)
+
+// HealthRecord is a single point-in-time health check result, as persisted
+// by the monitor on every run.
+type HealthRecord struct {
+    Endpoint  string
+    Timestamp time.Time
+    Status    string
+    LatencyMS int64
+    Error     string
+}
+
+// Store persists health-check snapshots and answers history queries over
+// them.
+type Store interface {
+    // RecordHealth appends a single health check result.
+    RecordHealth(ctx context.Context, rec HealthRecord) error
+    // LastHealthByEndpoint returns the most recent record for each
+    // endpoint the store has ever seen.
+    LastHealthByEndpoint(ctx context.Context) (map[string]HealthRecord, error)
+    // UptimeSince returns, per endpoint, the percentage (0-100) of recorded
+    // checks since the given time that reported a healthy status.
+    UptimeSince(ctx context.Context, since time.Time) (map[string]float64, error)
+    Close() error
+}
+
+// SQLiteStore is a Store backed by modernc.org/sqlite, a pure-Go driver
+// that needs no cgo toolchain, so the monitor binary stays easy to
+// cross-compile.
+type SQLiteStore struct {
+    db *sql.DB
+}
+
+// NewSQLiteStore opens (and, if needed, creates) the SQLite database at
+// path and ensures its schema is in place.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+    db, err := sql.Open("sqlite", path)
+    if err != nil {
+        return nil, fmt.Errorf("open sqlite store: %w", err)
+    }
+
+    const schema = `
+    CREATE TABLE IF NOT EXISTS health_checks (
+        endpoint   TEXT NOT NULL,
+        timestamp  DATETIME NOT NULL,
+        status     TEXT NOT NULL,
+        latency_ms INTEGER NOT NULL,
+        error      TEXT NOT NULL DEFAULT ''
+    );
+    CREATE INDEX IF NOT EXISTS idx_health_checks_endpoint_timestamp
+        ON health_checks (endpoint, timestamp);`
+
+    if _, err := db.ExecContext(context.Background(), schema); err != nil {
+        db.Close()
+        return nil, fmt.Errorf("migrate sqlite store: %w", err)
+    }
+
+    return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) RecordHealth(ctx context.Context, rec HealthRecord) error {
+    _, err := s.db.ExecContext(ctx,
+        `INSERT INTO health_checks (endpoint, timestamp, status, latency_ms, error) VALUES (?, ?, ?, ?, ?)`,
+        rec.Endpoint, rec.Timestamp, rec.Status, rec.LatencyMS, rec.Error,
+    )
+    if err != nil {
+        return fmt.Errorf("record health check: %w", err)
+    }
+    return nil
+}
+
+func (s *SQLiteStore) LastHealthByEndpoint(ctx context.Context) (map[string]HealthRecord, error) {
+    rows, err := s.db.QueryContext(ctx, `
+        SELECT endpoint, timestamp, status, latency_ms, error
+        FROM health_checks h
+        WHERE timestamp = (
+            SELECT MAX(timestamp) FROM health_checks WHERE endpoint = h.endpoint
+        )`)
+    if err != nil {
+        return nil, fmt.Errorf("query last health checks: %w", err)
+    }
+    defer rows.Close()
+
+    last := make(map[string]HealthRecord)
+    for rows.Next() {
+        var rec HealthRecord
+        if err := rows.Scan(&rec.Endpoint, &rec.Timestamp, &rec.Status, &rec.LatencyMS, &rec.Error); err != nil {
+            return nil, fmt.Errorf("scan health check: %w", err)
+        }
+        last[rec.Endpoint] = rec
+    }
+    return last, rows.Err()
+}
+
+func (s *SQLiteStore) UptimeSince(ctx context.Context, since time.Time) (map[string]float64, error) {
+    rows, err := s.db.QueryContext(ctx, `
+        SELECT endpoint, status FROM health_checks WHERE timestamp >= ?`, since)
+    if err != nil {
+        return nil, fmt.Errorf("query uptime: %w", err)
+    }
+    defer rows.Close()
+
+    type tally struct{ up, total int }
+    tallies := make(map[string]*tally)
+    for rows.Next() {
+        var endpoint, status string
+        if err := rows.Scan(&endpoint, &status); err != nil {
+            return nil, fmt.Errorf("scan uptime row: %w", err)
+        }
+        t, ok := tallies[endpoint]
+        if !ok {
+            t = &tally{}
+            tallies[endpoint] = t
+        }
+        t.total++
+        if status == "up" {
+            t.up++
+        }
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    uptime := make(map[string]float64, len(tallies))
+    for endpoint, t := range tallies {
+        uptime[endpoint] = float64(t.up) / float64(t.total) * 100
+    }
+    return uptime, nil
+}
+
+func (s *SQLiteStore) Close() error {
+    return s.db.Close()
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+    "context"
+    "path/filepath"
+    "testing"
+    "time"
+)
+
+func newTestStore(t *testing.T) *SQLiteStore {
+    t.Helper()
+
+    store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "monitor.db"))
+    if err != nil {
+        t.Fatalf("NewSQLiteStore() error = %v", err)
+    }
+    t.Cleanup(func() { store.Close() })
+
+    return store
+}
+
+func TestSQLiteStore_RecordAndLastHealthByEndpoint(t *testing.T) {
+    store := newTestStore(t)
+    ctx := context.Background()
+
+    older := HealthRecord{Endpoint: "https://geth.example.com", Timestamp: time.Now().Add(-time.Hour), Status: "down"}
+    newer := HealthRecord{Endpoint: "https://geth.example.com", Timestamp: time.Now(), Status: "up"}
+
+    if err := store.RecordHealth(ctx, older); err != nil {
+        t.Fatalf("RecordHealth() error = %v", err)
+    }
+    if err := store.RecordHealth(ctx, newer); err != nil {
+        t.Fatalf("RecordHealth() error = %v", err)
+    }
+
+    last, err := store.LastHealthByEndpoint(ctx)
+    if err != nil {
+        t.Fatalf("LastHealthByEndpoint() error = %v", err)
+    }
+
+    rec, ok := last["https://geth.example.com"]
+    if !ok {
+        t.Fatal("expected a record for geth endpoint")
+    }
+    if rec.Status != "up" {
+        t.Errorf("Expected most recent status 'up', got '%s'", rec.Status)
+    }
+}
+
+func TestSQLiteStore_UptimeSince(t *testing.T) {
+    store := newTestStore(t)
+    ctx := context.Background()
+
+    endpoint := "https://beacon.example.com"
+    records := []HealthRecord{
+        {Endpoint: endpoint, Timestamp: time.Now(), Status: "up"},
+        {Endpoint: endpoint, Timestamp: time.Now(), Status: "up"},
+        {Endpoint: endpoint, Timestamp: time.Now(), Status: "down"},
+    }
+    for _, rec := range records {
+        if err := store.RecordHealth(ctx, rec); err != nil {
+            t.Fatalf("RecordHealth() error = %v", err)
+        }
+    }
+
+    uptime, err := store.UptimeSince(ctx, time.Now().Add(-time.Minute))
+    if err != nil {
+        t.Fatalf("UptimeSince() error = %v", err)
+    }
+
+    want := float64(2) / float64(3) * 100
+    if got := uptime[endpoint]; got != want {
+        t.Errorf("Expected uptime %.4f, got %.4f", want, got)
+    }
+}
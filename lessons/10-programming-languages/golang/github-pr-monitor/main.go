@@ -2,31 +2,178 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"strings"
+	"sync"
 	"time"
 )
 
-type PullRequest struct {
-	Title  string `json:"title"`
-	Number int    `json:"number"`
-	User   struct {
+const graphqlURL = "https://api.github.com/graphql"
+
+// PRData is the flattened, report-ready view of a pull request returned
+// by the GraphQL query below.
+type PRData struct {
+	Repo               string
+	Number             int
+	Title              string
+	Author             string
+	BaseRef            string
+	URL                string
+	CreatedAt          time.Time
+	TimeOpened         string
+	IsDraft            bool
+	Mergeable          string // MERGEABLE, CONFLICTING, UNKNOWN
+	ReviewDecision     string // APPROVED, CHANGES_REQUESTED, REVIEW_REQUIRED, ""
+	CheckConclusion    string // SUCCESS, FAILURE, ERROR, PENDING, ""
+	RequestedReviewers []string
+	IsNew              bool // true if the store had no record of this PR before this run
+}
+
+type graphqlRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+type graphqlError struct {
+	Message string `json:"message"`
+}
+
+// prNodeFields is the set of PR fields fetched by both orgPullRequestsQuery
+// and repoPullRequestsQuery; keep the two in sync.
+const prNodeFields = `
+            number
+            title
+            url
+            isDraft
+            mergeable
+            createdAt
+            baseRefName
+            reviewDecision
+            author { login }
+            reviewRequests(first: 10) {
+              nodes {
+                requestedReviewer {
+                  ... on User { login }
+                  ... on Team { name }
+                }
+              }
+            }
+            commits(last: 1) {
+              nodes {
+                commit {
+                  statusCheckRollup { state }
+                }
+              }
+            }`
+
+// orgPullRequestsQuery pages through every repository in the org and, for
+// each one, up to the first page of open pull requests along with review
+// state, mergeability and the latest commit's check-suite conclusion.
+// Repositories with more than prPageSize open PRs are topped up by
+// repoPullRequestsQuery.
+var orgPullRequestsQuery = fmt.Sprintf(`
+query($org: String!, $after: String) {
+  organization(login: $org) {
+    repositories(first: 20, after: $after) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        name
+        pullRequests(states: OPEN, first: %d) {
+          pageInfo { hasNextPage endCursor }
+          nodes {%s
+          }
+        }
+      }
+    }
+  }
+}`, prPageSize, prNodeFields)
+
+// repoPullRequestsQuery fetches one more page of a single repository's
+// open pull requests, for repositories orgPullRequestsQuery truncated.
+var repoPullRequestsQuery = fmt.Sprintf(`
+query($org: String!, $repo: String!, $after: String) {
+  repository(owner: $org, name: $repo) {
+    pullRequests(states: OPEN, first: %d, after: $after) {
+      pageInfo { hasNextPage endCursor }
+      nodes {%s
+      }
+    }
+  }
+}`, prPageSize, prNodeFields)
+
+// prPageSize is the page size used for both the org-level and per-repo
+// pull request connections.
+const prPageSize = 50
+
+type prNode struct {
+	Number         int       `json:"number"`
+	Title          string    `json:"title"`
+	URL            string    `json:"url"`
+	IsDraft        bool      `json:"isDraft"`
+	Mergeable      string    `json:"mergeable"`
+	CreatedAt      time.Time `json:"createdAt"`
+	BaseRefName    string    `json:"baseRefName"`
+	ReviewDecision string    `json:"reviewDecision"`
+	Author         struct {
 		Login string `json:"login"`
-	} `json:"user"`
-	CreatedAt time.Time `json:"created_at"`
-	HtmlURL   string    `json:"html_url"`
-	Base      struct {
-		Ref string `json:"ref"`
-	} `json:"base"`
+	} `json:"author"`
+	ReviewRequests struct {
+		Nodes []struct {
+			RequestedReviewer struct {
+				Login string `json:"login"`
+				Name  string `json:"name"`
+			} `json:"requestedReviewer"`
+		} `json:"nodes"`
+	} `json:"reviewRequests"`
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup struct {
+					State string `json:"state"`
+				} `json:"statusCheckRollup"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
 }
 
-type PRData struct {
-	Repo       string
-	PR         PullRequest
-	TimeOpened string
+type prConnection struct {
+	PageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Nodes []prNode `json:"nodes"`
+}
+
+type orgPullRequestsResponse struct {
+	Data struct {
+		Organization struct {
+			Repositories struct {
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				Nodes []struct {
+					Name         string       `json:"name"`
+					PullRequests prConnection `json:"pullRequests"`
+				} `json:"nodes"`
+			} `json:"repositories"`
+		} `json:"organization"`
+	} `json:"data"`
+	Errors []graphqlError `json:"errors"`
+}
+
+type repoPullRequestsResponse struct {
+	Data struct {
+		Repository struct {
+			PullRequests prConnection `json:"pullRequests"`
+		} `json:"repository"`
+	} `json:"data"`
+	Errors []graphqlError `json:"errors"`
 }
 
 func getEnvOrExit(key string) string {
@@ -42,70 +189,176 @@ func getEnvOptional(key string) string {
 	return os.Getenv(key)
 }
 
+func getEnv(key, defaultVal string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return defaultVal
+}
+
 func main() {
+	store, err := NewSQLiteStore(getEnv("DB_PATH", "pr-monitor.db"))
+	if err != nil {
+		fmt.Println("Error opening history store:", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	if len(os.Args) > 1 && os.Args[1] == "history" {
+		runHistory(store, parseHistoryWindow(getEnvOptional("HISTORY_WINDOW")))
+		return
+	}
+
 	githubToken := getEnvOrExit("GITHUB_TOKEN")
 	githubOrg := getEnvOrExit("ORG_NAME")
-	teamsWebhook := getEnvOptional("TEAMS_WEBHOOK")
+	minAge := parseMinAge(minAgeArg(os.Args[1:], getEnvOptional("MIN_AGE")))
+
+	allPRs, err := getAllOpenPRs(githubToken, githubOrg, minAge)
+	if err != nil {
+		fmt.Println("Error obteniendo pull requests:", err)
+		os.Exit(1)
+	}
 
-	repos, err := getRepos(githubToken, githubOrg)
+	allPRs, err = annotateAndPersist(store, allPRs)
 	if err != nil {
-		fmt.Println("Error obteniendo repositorios:", err)
+		fmt.Println("Error updating PR history:", err)
 		os.Exit(1)
 	}
 
-	var report string
-	report += fmt.Sprintf("## Pending Pull Requests in organization %s\n\n", githubOrg)
-	report += "| Repo | PR | Author | Target Branch | Time Open | Link |\n"
-	report += "|------|----|--------|--------------|-----------|------|\n"
+	var consoleReport string
+	consoleReport += fmt.Sprintf("## Pending Pull Requests in organization %s\n\n", githubOrg)
+	consoleReport += "| Repo | PR | Author | Target Branch | Time Open | CI | Review | Link |\n"
+	consoleReport += "|------|----|--------|--------------|-----------|----|--------|------|\n"
 
-	var allPRs []PRData
-	validBranches := map[string]bool{
-		"development": true,
-		"qa":          true,
-		"release":     true,
-		"main":        true,
-		"master":      true, // Incluyo master también por si acaso
+	for _, pr := range allPRs {
+		consoleReport += fmt.Sprintf("| %s | #%d %s | %s | %s | %s | %s | %s | [Ver PR](%s) |\n",
+			pr.Repo, pr.Number, pr.Title, pr.Author, pr.BaseRef, pr.TimeOpened,
+			pr.CheckConclusion, pr.ReviewDecision, pr.URL)
 	}
 
-	for _, repo := range repos {
-		prs, err := getOpenPRs(githubToken, githubOrg, repo)
-		if err != nil {
-			fmt.Printf("Error obteniendo PRs de %s: %v\n", repo, err)
-			continue
-		}
+	// Print the report to the console
+	fmt.Println(consoleReport)
+
+	sinks := alertSinksFromEnv()
+	if len(sinks) == 0 {
+		fmt.Println("ALERT_SINKS not set. Report shown only in console.")
+		return
+	}
+
+	report := Report{Org: githubOrg, PRs: allPRs}
+	fanOutCtx, cancel := context.WithTimeout(context.Background(), fanOutTimeout)
+	defer cancel()
+	if err := fanOutReport(fanOutCtx, sinks, report); err != nil {
+		fmt.Println("Error sending report to one or more sinks:", err)
+		os.Exit(1)
+	}
+	fmt.Println("Report sent to all configured sinks successfully.")
+}
 
-		for _, pr := range prs {
-			// Filtrar solo PRs hacia las ramas permitidas
-			if !validBranches[pr.Base.Ref] {
-				continue
+// alertSinksFromEnv builds one AlertSink per entry in the comma-separated
+// ALERT_SINKS env var (e.g. "teams,slack"), reading each sink's own
+// webhook URL from its dedicated env var. Unknown or unconfigured sinks
+// are skipped with a warning rather than failing the whole run.
+func alertSinksFromEnv() []AlertSink {
+	var sinks []AlertSink
+
+	for _, name := range strings.Split(getEnvOptional("ALERT_SINKS"), ",") {
+		switch strings.TrimSpace(name) {
+		case "teams":
+			if url := getEnvOptional("TEAMS_WEBHOOK"); url != "" {
+				sinks = append(sinks, TeamsSink{WebhookURL: url})
+			} else {
+				fmt.Println("ALERT_SINKS includes teams but TEAMS_WEBHOOK is not set, skipping")
+			}
+		case "slack":
+			if url := getEnvOptional("SLACK_WEBHOOK"); url != "" {
+				sinks = append(sinks, SlackSink{WebhookURL: url})
+			} else {
+				fmt.Println("ALERT_SINKS includes slack but SLACK_WEBHOOK is not set, skipping")
+			}
+		case "discord":
+			if url := getEnvOptional("DISCORD_WEBHOOK"); url != "" {
+				sinks = append(sinks, DiscordSink{WebhookURL: url})
+			} else {
+				fmt.Println("ALERT_SINKS includes discord but DISCORD_WEBHOOK is not set, skipping")
 			}
+		case "webhook":
+			if url := getEnvOptional("WEBHOOK_URL"); url != "" {
+				sinks = append(sinks, WebhookSink{URL: url})
+			} else {
+				fmt.Println("ALERT_SINKS includes webhook but WEBHOOK_URL is not set, skipping")
+			}
+		case "":
+			// ALERT_SINKS unset or has a stray empty entry; nothing to do.
+		default:
+			fmt.Printf("Unknown alert sink %q, skipping\n", name)
+		}
+	}
+
+	return sinks
+}
 
-			dur := time.Since(pr.CreatedAt).Round(time.Hour)
-			report += fmt.Sprintf("| %s | #%d %s | %s | %s | %s | [Ver PR](%s) |\n",
-				repo, pr.Number, pr.Title, pr.User.Login, pr.Base.Ref, dur, pr.HtmlURL)
+// fanOutTimeout bounds the whole fan-out so a single unresponsive sink
+// can't wedge the run; each sink's own HTTP call is bounded further by
+// sinkHTTPTimeout.
+const fanOutTimeout = 30 * time.Second
+
+// fanOutReport sends report to every sink concurrently and aggregates any
+// errors so a single failing sink doesn't keep the others from reporting.
+func fanOutReport(ctx context.Context, sinks []AlertSink, report Report) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(sinks))
+
+	for i, sink := range sinks {
+		wg.Add(1)
+		go func(i int, sink AlertSink) {
+			defer wg.Done()
+			errs[i] = sink.Send(ctx, report)
+		}(i, sink)
+	}
+	wg.Wait()
 
-			// Agregar a la lista para Teams
-			allPRs = append(allPRs, PRData{
-				Repo:       repo,
-				PR:         pr,
-				TimeOpened: formatDuration(dur),
-			})
+	var combined error
+	for _, err := range errs {
+		if err != nil {
+			if combined == nil {
+				combined = err
+			} else {
+				combined = fmt.Errorf("%w; %v", combined, err)
+			}
 		}
 	}
+	return combined
+}
 
-	// Print the report to the console
-	fmt.Println(report)
-
-	// Send to Teams only if the webhook is configured
-	if teamsWebhook != "" {
-		if err := sendToTeams(teamsWebhook, githubOrg, allPRs); err != nil {
-			fmt.Println("Error sending to Teams:", err)
-		} else {
-			fmt.Println("Report sent to Teams successfully.")
+// minAgeArg returns the value of a "--min-age=<duration>" or "--min-age
+// <duration>" flag if present in args, otherwise envFallback (the MIN_AGE
+// env var). The flag takes precedence over the env var.
+func minAgeArg(args []string, envFallback string) string {
+	for i, arg := range args {
+		if v, ok := strings.CutPrefix(arg, "--min-age="); ok {
+			return v
 		}
-	} else {
-		fmt.Println("TEAMS_WEBHOOK not set. Report shown only in console.")
+		if arg == "--min-age" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return envFallback
+}
+
+// parseMinAge parses a duration string like "24h" (from --min-age or the
+// MIN_AGE env var) and falls back to 0 (no filtering) if it is unset or
+// malformed.
+func parseMinAge(raw string) time.Duration {
+	if raw == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		fmt.Printf("MIN_AGE inválido (%q), se ignora el filtro: %v\n", raw, err)
+		return 0
 	}
+	return d
 }
 
 func formatDuration(dur time.Duration) string {
@@ -118,292 +371,249 @@ func formatDuration(dur time.Duration) string {
 	return fmt.Sprintf("%d hours", hours)
 }
 
-func getRepos(githubToken, githubOrg string) ([]string, error) {
+// getAllOpenPRs fetches every open PR across every repository in the org,
+// following the repositories pagination cursor until the organization is
+// exhausted and, for any repo with more than prPageSize open PRs, paging
+// that repo's pull requests separately via fetchRemainingRepoPRs. PRs
+// targeting a branch outside validBranches, or younger than minAge, are
+// dropped.
+func getAllOpenPRs(githubToken, githubOrg string, minAge time.Duration) ([]PRData, error) {
+	validBranches := map[string]bool{
+		"development": true,
+		"qa":          true,
+		"release":     true,
+		"main":        true,
+		"master":      true, // Incluyo master también por si acaso
+	}
+
 	client := &http.Client{}
-	var repos []string
-	page := 1
-	for {
-		url := fmt.Sprintf("https://api.github.com/orgs/%s/repos?per_page=100&page=%d", githubOrg, page)
-		req, _ := http.NewRequest("GET", url, nil)
-		req.Header.Set("Authorization", "Bearer "+githubToken)
-		req.Header.Set("Accept", "application/vnd.github+json")
+	var allPRs []PRData
+	after := ""
 
-		resp, err := client.Do(req)
+	for {
+		resp, err := queryOrgPullRequests(client, githubToken, githubOrg, after)
 		if err != nil {
 			return nil, err
 		}
-		defer resp.Body.Close()
+		if len(resp.Errors) > 0 {
+			return nil, fmt.Errorf("github graphql error: %s", resp.Errors[0].Message)
+		}
 
-		body, _ := ioutil.ReadAll(resp.Body)
+		repos := resp.Data.Organization.Repositories
+		for _, repo := range repos.Nodes {
+			prs := repo.PullRequests.Nodes
+			if repo.PullRequests.PageInfo.HasNextPage {
+				more, err := fetchRemainingRepoPRs(client, githubToken, githubOrg, repo.Name, repo.PullRequests.PageInfo.EndCursor)
+				if err != nil {
+					return nil, fmt.Errorf("page remaining PRs for %s: %w", repo.Name, err)
+				}
+				prs = append(prs, more...)
+			}
 
-		var data []struct {
-			Name string `json:"name"`
-		}
-		if err := json.Unmarshal(body, &data); err != nil {
-			return nil, err
+			for _, pr := range prs {
+				if !validBranches[pr.BaseRefName] {
+					continue
+				}
+
+				age := time.Since(pr.CreatedAt)
+				if age < minAge {
+					continue
+				}
+
+				var reviewers []string
+				for _, rr := range pr.ReviewRequests.Nodes {
+					if rr.RequestedReviewer.Login != "" {
+						reviewers = append(reviewers, rr.RequestedReviewer.Login)
+					} else if rr.RequestedReviewer.Name != "" {
+						reviewers = append(reviewers, rr.RequestedReviewer.Name)
+					}
+				}
+
+				checkConclusion := ""
+				if commits := pr.Commits.Nodes; len(commits) > 0 {
+					checkConclusion = commits[0].Commit.StatusCheckRollup.State
+				}
+
+				allPRs = append(allPRs, PRData{
+					Repo:               repo.Name,
+					Number:             pr.Number,
+					Title:              pr.Title,
+					Author:             pr.Author.Login,
+					BaseRef:            pr.BaseRefName,
+					URL:                pr.URL,
+					CreatedAt:          pr.CreatedAt,
+					TimeOpened:         formatDuration(age.Round(time.Hour)),
+					IsDraft:            pr.IsDraft,
+					Mergeable:          pr.Mergeable,
+					ReviewDecision:     pr.ReviewDecision,
+					CheckConclusion:    checkConclusion,
+					RequestedReviewers: reviewers,
+				})
+			}
 		}
-		if len(data) == 0 {
+
+		if !repos.PageInfo.HasNextPage {
 			break
 		}
-		for _, r := range data {
-			repos = append(repos, r.Name)
-		}
-		page++
+		after = repos.PageInfo.EndCursor
 	}
-	return repos, nil
+
+	return allPRs, nil
 }
 
-func getOpenPRs(githubToken, githubOrg, repo string) ([]PullRequest, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls?state=open", githubOrg, repo)
-	req, _ := http.NewRequest("GET", url, nil)
+func queryOrgPullRequests(client *http.Client, githubToken, githubOrg, after string) (*orgPullRequestsResponse, error) {
+	variables := map[string]any{"org": githubOrg}
+	if after != "" {
+		variables["after"] = after
+	}
+
+	body, err := json.Marshal(graphqlRequest{Query: orgPullRequestsQuery, Variables: variables})
+	if err != nil {
+		return nil, fmt.Errorf("encode graphql request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", graphqlURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build graphql request: %w", err)
+	}
 	req.Header.Set("Authorization", "Bearer "+githubToken)
-	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	body, _ := ioutil.ReadAll(resp.Body)
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read graphql response: %w", err)
+	}
 
-	var prs []PullRequest
-	if err := json.Unmarshal(body, &prs); err != nil {
-		return nil, err
+	var parsed orgPullRequestsResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, fmt.Errorf("decode graphql response: %w", err)
 	}
 
-	return prs, nil
+	return &parsed, nil
 }
 
-func sendToTeams(teamsWebhook, githubOrg string, prData []PRData) error {
-	card := createAdaptiveCard(githubOrg, prData)
+// fetchRemainingRepoPRs pages through repoName's open PRs starting after
+// cursor, for repos whose PR count exceeded prPageSize in the org query.
+func fetchRemainingRepoPRs(client *http.Client, githubToken, githubOrg, repoName, after string) ([]prNode, error) {
+	var nodes []prNode
+
+	for after != "" {
+		resp, err := queryRepoPullRequests(client, githubToken, githubOrg, repoName, after)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Errors) > 0 {
+			return nil, fmt.Errorf("github graphql error: %s", resp.Errors[0].Message)
+		}
+
+		prs := resp.Data.Repository.PullRequests
+		nodes = append(nodes, prs.Nodes...)
 
-	// Volver al formato simple que funcionaba
-	payload := map[string]interface{}{
-		"type": "message",
-		"attachments": []map[string]interface{}{
-			{
-				"contentType": "application/vnd.microsoft.card.adaptive",
-				"content":     card,
-			},
-		},
+		after = ""
+		if prs.PageInfo.HasNextPage {
+			after = prs.PageInfo.EndCursor
+		}
 	}
 
-	b, _ := json.Marshal(payload)
+	return nodes, nil
+}
 
-	// Print the payload for debugging
-	fmt.Println("Payload to Teams:")
-	fmt.Println(string(b))
+func queryRepoPullRequests(client *http.Client, githubToken, githubOrg, repoName, after string) (*repoPullRequestsResponse, error) {
+	variables := map[string]any{"org": githubOrg, "repo": repoName, "after": after}
 
-	resp, err := http.Post(teamsWebhook, "application/json", bytes.NewBuffer(b))
+	body, err := json.Marshal(graphqlRequest{Query: repoPullRequestsQuery, Variables: variables})
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("encode graphql request: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode >= 300 {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("Teams webhook error: %s", string(body))
+	req, err := http.NewRequest("POST", graphqlURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build graphql request: %w", err)
 	}
+	req.Header.Set("Authorization", "Bearer "+githubToken)
+	req.Header.Set("Content-Type", "application/json")
 
-	return nil
-}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
 
-func createAdaptiveCard(githubOrg string, prData []PRData) map[string]interface{} {
-	// Card header con Container de ancho completo
-	elements := []map[string]interface{}{
-		{
-			"type": "Container",
-			"items": []map[string]interface{}{
-				{
-					"type":   "TextBlock",
-					"text":   fmt.Sprintf("📋 Pending Pull Requests - %s", githubOrg),
-					"weight": "Bolder",
-					"size":   "Large",
-					"color":  "Accent",
-					"wrap":   true,
-				},
-				{
-					"type":    "TextBlock",
-					"text":    fmt.Sprintf("Total open PRs: %d", len(prData)),
-					"weight":  "Lighter",
-					"spacing": "None",
-					"wrap":    true,
-				},
-			},
-			"style": "emphasis",
-		},
-	}
-
-	// Group PRs by urgency
-	urgent := []PRData{}
-	old := []PRData{}
-	recent := []PRData{}
-
-	for _, pr := range prData {
-		hours := time.Since(pr.PR.CreatedAt).Hours()
-		if hours > 24*30 { // More than 30 days
-			urgent = append(urgent, pr)
-		} else if hours > 24*7 { // More than 7 days
-			old = append(old, pr)
-		} else {
-			recent = append(recent, pr)
-		}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read graphql response: %w", err)
 	}
 
-	// Urgent PRs section
-	if len(urgent) > 0 {
-		elements = append(elements, map[string]interface{}{
-			"type": "Container",
-			"items": []map[string]interface{}{
-				{
-					"type":    "TextBlock",
-					"text":    "🚨 Urgent PRs (+30 days)",
-					"weight":  "Bolder",
-					"size":    "Medium",
-					"color":   "Attention",
-					"spacing": "Medium",
-					"wrap":    true,
-				},
-			},
-		})
-
-		for _, pr := range urgent {
-			elements = append(elements, createPRBlock(pr, "Attention"))
-		}
+	var parsed repoPullRequestsResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, fmt.Errorf("decode graphql response: %w", err)
 	}
 
-	// Old PRs section
-	if len(old) > 0 {
-		elements = append(elements, map[string]interface{}{
-			"type": "Container",
-			"items": []map[string]interface{}{
-				{
-					"type":    "TextBlock",
-					"text":    "⚠️ Old PRs (7-30 days)",
-					"weight":  "Bolder",
-					"size":    "Medium",
-					"color":   "Warning",
-					"spacing": "Medium",
-					"wrap":    true,
-				},
-			},
-		})
-
-		for _, pr := range old {
-			elements = append(elements, createPRBlock(pr, "Warning"))
-		}
+	return &parsed, nil
+}
+
+// annotateAndPersist marks each PR as new if it wasn't known to the store
+// before this run, then records the current run's snapshot.
+func annotateAndPersist(store Store, prs []PRData) ([]PRData, error) {
+	ctx := context.Background()
+
+	known, err := store.KnownPRs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load known prs: %w", err)
 	}
 
-	// Recent PRs section
-	if len(recent) > 0 {
-		elements = append(elements, map[string]interface{}{
-			"type": "Container",
-			"items": []map[string]interface{}{
-				{
-					"type":    "TextBlock",
-					"text":    "✅ Recent PRs (<7 days)",
-					"weight":  "Bolder",
-					"size":    "Medium",
-					"color":   "Good",
-					"spacing": "Medium",
-					"wrap":    true,
-				},
-			},
-		})
-
-		for _, pr := range recent {
-			elements = append(elements, createPRBlock(pr, "Good"))
+	now := time.Now()
+	for i, pr := range prs {
+		if _, seenBefore := known[prKey(pr.Repo, pr.Number)]; !seenBefore {
+			prs[i].IsNew = true
+		}
+		if err := store.UpsertPR(ctx, pr.Repo, pr.Number, pr.BaseRef, now); err != nil {
+			return nil, fmt.Errorf("persist pr %s#%d: %w", pr.Repo, pr.Number, err)
 		}
 	}
 
-	return map[string]interface{}{
-		"type":    "AdaptiveCard",
-		"version": "1.2",
-		"body":    elements,
-		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
-		// Configuración específica para Teams para ancho completo
-		"msteams": map[string]interface{}{
-			"width": "Full",
-		},
+	return prs, nil
+}
+
+// parseHistoryWindow parses the HISTORY_WINDOW env var (a Go duration
+// like "168h") and falls back to 7 days if it is unset or malformed.
+func parseHistoryWindow(raw string) time.Duration {
+	const defaultWindow = 7 * 24 * time.Hour
+	if raw == "" {
+		return defaultWindow
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		fmt.Printf("HISTORY_WINDOW inválido (%q), usando %s: %v\n", raw, defaultWindow, err)
+		return defaultWindow
 	}
+	return d
 }
 
-func createPRBlock(pr PRData, colorTheme string) map[string]interface{} {
-	// Emoji for each branch type
-	branchEmoji := map[string]string{
-		"main":        "🚀",
-		"master":      "🚀",
-		"release":     "📦",
-		"qa":          "🧪",
-		"development": "🔧",
-	}
-
-	emoji, exists := branchEmoji[pr.PR.Base.Ref]
-	if !exists {
-		emoji = "📝"
-	}
-
-	return map[string]interface{}{
-		"type":  "Container",
-		"style": "emphasis",
-		"width": "stretch", // Forzar que el container use todo el ancho
-		"items": []map[string]interface{}{
-			{
-				"type":  "ColumnSet",
-				"width": "stretch", // Asegurar que el ColumnSet también se expanda
-				"columns": []map[string]interface{}{
-					{
-						"type":  "Column",
-						"width": "stretch", // Mantener stretch para la primera columna
-						"items": []map[string]interface{}{
-							{
-								"type":   "TextBlock",
-								"text":   fmt.Sprintf("**%s** #%d", pr.Repo, pr.PR.Number),
-								"weight": "Bolder",
-								"size":   "Medium",
-								"wrap":   true,
-							},
-							{
-								"type":    "TextBlock",
-								"text":    pr.PR.Title,
-								"wrap":    true,
-								"spacing": "None",
-								"size":    "Default",
-								"weight":  "Default",
-							},
-							{
-								"type":    "TextBlock",
-								"text":    fmt.Sprintf("👤 %s • %s %s • ⏰ %s", pr.PR.User.Login, emoji, pr.PR.Base.Ref, pr.TimeOpened),
-								"size":    "Small",
-								"color":   "Accent",
-								"spacing": "None",
-								"wrap":    true,
-							},
-						},
-					},
-					{
-						"type":  "Column",
-						"width": "auto",
-						"items": []map[string]interface{}{
-							{
-								"type": "ActionSet",
-								"actions": []map[string]interface{}{
-									{
-										"type":  "Action.OpenUrl",
-										"title": "View PR",
-										"url":   pr.PR.HtmlURL,
-										"style": "positive",
-									},
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-		"spacing":   "Small",
-		"separator": true,
+// runHistory prints each known PR's age trend (time since first seen)
+// for PRs last seen within window, then returns so main can exit.
+func runHistory(store Store, window time.Duration) {
+	known, err := store.KnownPRs(context.Background())
+	if err != nil {
+		fmt.Println("Error reading PR history:", err)
+		os.Exit(1)
+	}
+
+	cutoff := time.Now().Add(-window)
+	fmt.Printf("PR age trend (last seen within %s):\n", window)
+	for _, rec := range known {
+		if rec.LastSeen.Before(cutoff) {
+			continue
+		}
+		age := time.Since(rec.FirstSeen).Round(time.Hour)
+		fmt.Printf("  %-40s first seen %s ago, last seen %s\n",
+			prKey(rec.Repo, rec.Number), age, rec.LastSeen.Format(time.RFC3339))
 	}
 }
+
@@ -0,0 +1,408 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Report is the sink-agnostic view of a run: the org it covers and every
+// open PR that survived filtering. Each AlertSink renders it however fits
+// its own platform.
+type Report struct {
+	Org string
+	PRs []PRData
+}
+
+// sinkHTTPTimeout bounds how long postJSON waits on a single sink so one
+// unresponsive webhook can't wedge the whole fan-out.
+const sinkHTTPTimeout = 10 * time.Second
+
+var sinkHTTPClient = &http.Client{Timeout: sinkHTTPTimeout}
+
+// AlertSink delivers a Report somewhere (Teams, Slack, Discord, a generic
+// webhook, ...). Each implementation owns its own rendering.
+type AlertSink interface {
+	Send(ctx context.Context, report Report) error
+}
+
+func postJSON(ctx context.Context, url string, payload any) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := sinkHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("webhook returned %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// TeamsSink posts the report as a Microsoft Teams adaptive card.
+type TeamsSink struct {
+	WebhookURL string
+}
+
+func (s TeamsSink) Send(ctx context.Context, report Report) error {
+	card := createAdaptiveCard(report.Org, report.PRs)
+	payload := map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{
+			{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content":     card,
+			},
+		},
+	}
+	return postJSON(ctx, s.WebhookURL, payload)
+}
+
+func createAdaptiveCard(githubOrg string, prData []PRData) map[string]interface{} {
+	// Card header con Container de ancho completo
+	elements := []map[string]interface{}{
+		{
+			"type": "Container",
+			"items": []map[string]interface{}{
+				{
+					"type":   "TextBlock",
+					"text":   fmt.Sprintf("📋 Pending Pull Requests - %s", githubOrg),
+					"weight": "Bolder",
+					"size":   "Large",
+					"color":  "Accent",
+					"wrap":   true,
+				},
+				{
+					"type":    "TextBlock",
+					"text":    fmt.Sprintf("Total open PRs: %d", len(prData)),
+					"weight":  "Lighter",
+					"spacing": "None",
+					"wrap":    true,
+				},
+			},
+			"style": "emphasis",
+		},
+	}
+
+	// Group PRs by urgency
+	urgent := []PRData{}
+	old := []PRData{}
+	recent := []PRData{}
+
+	for _, pr := range prData {
+		hours := time.Since(pr.CreatedAt).Hours()
+		if hours > 24*30 { // More than 30 days
+			urgent = append(urgent, pr)
+		} else if hours > 24*7 { // More than 7 days
+			old = append(old, pr)
+		} else {
+			recent = append(recent, pr)
+		}
+	}
+
+	// Urgent PRs section
+	if len(urgent) > 0 {
+		elements = append(elements, map[string]interface{}{
+			"type": "Container",
+			"items": []map[string]interface{}{
+				{
+					"type":    "TextBlock",
+					"text":    "🚨 Urgent PRs (+30 days)",
+					"weight":  "Bolder",
+					"size":    "Medium",
+					"color":   "Attention",
+					"spacing": "Medium",
+					"wrap":    true,
+				},
+			},
+		})
+
+		for _, pr := range urgent {
+			elements = append(elements, createPRBlock(pr, "Attention"))
+		}
+	}
+
+	// Old PRs section
+	if len(old) > 0 {
+		elements = append(elements, map[string]interface{}{
+			"type": "Container",
+			"items": []map[string]interface{}{
+				{
+					"type":    "TextBlock",
+					"text":    "⚠️ Old PRs (7-30 days)",
+					"weight":  "Bolder",
+					"size":    "Medium",
+					"color":   "Warning",
+					"spacing": "Medium",
+					"wrap":    true,
+				},
+			},
+		})
+
+		for _, pr := range old {
+			elements = append(elements, createPRBlock(pr, "Warning"))
+		}
+	}
+
+	// Recent PRs section
+	if len(recent) > 0 {
+		elements = append(elements, map[string]interface{}{
+			"type": "Container",
+			"items": []map[string]interface{}{
+				{
+					"type":    "TextBlock",
+					"text":    "✅ Recent PRs (<7 days)",
+					"weight":  "Bolder",
+					"size":    "Medium",
+					"color":   "Good",
+					"spacing": "Medium",
+					"wrap":    true,
+				},
+			},
+		})
+
+		for _, pr := range recent {
+			elements = append(elements, createPRBlock(pr, "Good"))
+		}
+	}
+
+	return map[string]interface{}{
+		"type":    "AdaptiveCard",
+		"version": "1.2",
+		"body":    elements,
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		// Configuración específica para Teams para ancho completo
+		"msteams": map[string]interface{}{
+			"width": "Full",
+		},
+	}
+}
+
+func createPRBlock(pr PRData, colorTheme string) map[string]interface{} {
+	// Emoji for each branch type
+	branchEmoji := map[string]string{
+		"main":        "🚀",
+		"master":      "🚀",
+		"release":     "📦",
+		"qa":          "🧪",
+		"development": "🔧",
+	}
+
+	emoji, exists := branchEmoji[pr.BaseRef]
+	if !exists {
+		emoji = "📝"
+	}
+
+	title := fmt.Sprintf("**%s** #%d", pr.Repo, pr.Number)
+	if pr.IsNew {
+		title = "🆕 " + title
+	}
+	if pr.IsDraft {
+		title += " (draft)"
+	}
+
+	statusLine := fmt.Sprintf("👤 %s • %s %s • ⏰ %s", pr.Author, emoji, pr.BaseRef, pr.TimeOpened)
+	if len(pr.RequestedReviewers) > 0 {
+		statusLine += " • waiting on @" + pr.RequestedReviewers[0]
+		if len(pr.RequestedReviewers) > 1 {
+			statusLine += fmt.Sprintf(" (+%d)", len(pr.RequestedReviewers)-1)
+		}
+	}
+
+	items := []map[string]interface{}{
+		{
+			"type":   "TextBlock",
+			"text":   title,
+			"weight": "Bolder",
+			"size":   "Medium",
+			"wrap":   true,
+		},
+		{
+			"type":    "TextBlock",
+			"text":    pr.Title,
+			"wrap":    true,
+			"spacing": "None",
+			"size":    "Default",
+			"weight":  "Default",
+		},
+		{
+			"type":    "TextBlock",
+			"text":    statusLine,
+			"size":    "Small",
+			"color":   "Accent",
+			"spacing": "None",
+			"wrap":    true,
+		},
+	}
+
+	if badge, color, ok := signalBadge(pr); ok {
+		items = append(items, map[string]interface{}{
+			"type":    "TextBlock",
+			"text":    badge,
+			"size":    "Small",
+			"color":   color,
+			"weight":  "Bolder",
+			"spacing": "None",
+			"wrap":    true,
+		})
+	}
+
+	return map[string]interface{}{
+		"type":  "Container",
+		"style": "emphasis",
+		"width": "stretch", // Forzar que el container use todo el ancho
+		"items": []map[string]interface{}{
+			{
+				"type":  "ColumnSet",
+				"width": "stretch", // Asegurar que el ColumnSet también se expanda
+				"columns": []map[string]interface{}{
+					{
+						"type":  "Column",
+						"width": "stretch", // Mantener stretch para la primera columna
+						"items": items,
+					},
+					{
+						"type":  "Column",
+						"width": "auto",
+						"items": []map[string]interface{}{
+							{
+								"type": "ActionSet",
+								"actions": []map[string]interface{}{
+									{
+										"type":  "Action.OpenUrl",
+										"title": "View PR",
+										"url":   pr.URL,
+										"style": "positive",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"spacing":   "Small",
+		"separator": true,
+	}
+}
+
+// signalBadge returns the highest-priority warning badge for a PR: CI
+// failing beats changes-requested, since a red build blocks merge
+// regardless of review state.
+func signalBadge(pr PRData) (text, color string, ok bool) {
+	if pr.CheckConclusion == "FAILURE" || pr.CheckConclusion == "ERROR" {
+		return "🔴 CI failing", "Attention", true
+	}
+	if pr.ReviewDecision == "CHANGES_REQUESTED" {
+		return "🟡 Changes requested", "Warning", true
+	}
+	if pr.Mergeable == "CONFLICTING" {
+		return "🟡 Merge conflicts", "Warning", true
+	}
+	return "", "", false
+}
+
+// SlackSink posts the report as Slack Block Kit blocks.
+type SlackSink struct {
+	WebhookURL string
+}
+
+func (s SlackSink) Send(ctx context.Context, report Report) error {
+	blocks := []map[string]interface{}{
+		{
+			"type": "header",
+			"text": map[string]interface{}{
+				"type": "plain_text",
+				"text": fmt.Sprintf("📋 Pending Pull Requests - %s", report.Org),
+			},
+		},
+		{
+			"type": "section",
+			"text": map[string]interface{}{
+				"type": "mrkdwn",
+				"text": fmt.Sprintf("Total open PRs: *%d*", len(report.PRs)),
+			},
+		},
+	}
+
+	for _, pr := range report.PRs {
+		prefix := ""
+		if pr.IsNew {
+			prefix = "🆕 "
+		}
+		text := fmt.Sprintf("%s*<%s|%s #%d>*\n%s\n👤 %s • %s • ⏰ %s",
+			prefix, pr.URL, pr.Repo, pr.Number, pr.Title, pr.Author, pr.BaseRef, pr.TimeOpened)
+		if badge, _, ok := signalBadge(pr); ok {
+			text += "\n" + badge
+		}
+
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": map[string]interface{}{
+				"type": "mrkdwn",
+				"text": text,
+			},
+		})
+	}
+
+	payload := map[string]interface{}{"blocks": blocks}
+	return postJSON(ctx, s.WebhookURL, payload)
+}
+
+// DiscordSink posts the report as Discord embeds.
+type DiscordSink struct {
+	WebhookURL string
+}
+
+func (s DiscordSink) Send(ctx context.Context, report Report) error {
+	embeds := make([]map[string]interface{}, 0, len(report.PRs))
+	for _, pr := range report.PRs {
+		description := fmt.Sprintf("%s\n👤 %s • %s • ⏰ %s", pr.Title, pr.Author, pr.BaseRef, pr.TimeOpened)
+		if badge, _, ok := signalBadge(pr); ok {
+			description += "\n" + badge
+		}
+
+		title := fmt.Sprintf("%s #%d", pr.Repo, pr.Number)
+		if pr.IsNew {
+			title = "🆕 " + title
+		}
+
+		embeds = append(embeds, map[string]interface{}{
+			"title":       title,
+			"url":         pr.URL,
+			"description": description,
+		})
+	}
+
+	payload := map[string]interface{}{
+		"content": fmt.Sprintf("📋 Pending Pull Requests - %s (%d open)", report.Org, len(report.PRs)),
+		"embeds":  embeds,
+	}
+	return postJSON(ctx, s.WebhookURL, payload)
+}
+
+// WebhookSink posts the raw Report as JSON to a generic endpoint, for
+// callers that want to build their own presentation.
+type WebhookSink struct {
+	URL string
+}
+
+func (s WebhookSink) Send(ctx context.Context, report Report) error {
+	return postJSON(ctx, s.URL, report)
+}
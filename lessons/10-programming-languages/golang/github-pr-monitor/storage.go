@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// PRRecord is a persisted snapshot of a PR's lifetime, updated on every
+// run: FirstSeen is set once, LastSeen advances each time the PR is still
+// open.
+type PRRecord struct {
+	Repo       string
+	Number     int
+	BaseBranch string
+	FirstSeen  time.Time
+	LastSeen   time.Time
+	AgeHours   float64
+}
+
+// Store persists PR history across runs so the reporter can tell which
+// PRs are new since the last run and how their age trends over time.
+type Store interface {
+	// UpsertPR records repo/number as seen at seenAt, setting FirstSeen
+	// only the first time it's observed.
+	UpsertPR(ctx context.Context, repo string, number int, baseBranch string, seenAt time.Time) error
+	// KnownPRs returns every PR the store has ever recorded, keyed by
+	// "repo#number".
+	KnownPRs(ctx context.Context) (map[string]PRRecord, error)
+	Close() error
+}
+
+// SQLiteStore is a Store backed by modernc.org/sqlite, a pure-Go driver
+// that needs no cgo toolchain.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite store: %w", err)
+	}
+
+	const schema = `
+    CREATE TABLE IF NOT EXISTS pull_requests (
+        repo        TEXT NOT NULL,
+        number      INTEGER NOT NULL,
+        base_branch TEXT NOT NULL,
+        first_seen  DATETIME NOT NULL,
+        last_seen   DATETIME NOT NULL,
+        age_hours   REAL NOT NULL DEFAULT 0,
+        PRIMARY KEY (repo, number)
+    );`
+	if _, err := db.ExecContext(context.Background(), schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate sqlite store: %w", err)
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) UpsertPR(ctx context.Context, repo string, number int, baseBranch string, seenAt time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+        INSERT INTO pull_requests (repo, number, base_branch, first_seen, last_seen, age_hours)
+        VALUES (?, ?, ?, ?, ?, 0)
+        ON CONFLICT (repo, number) DO UPDATE SET
+            last_seen = excluded.last_seen,
+            base_branch = excluded.base_branch,
+            age_hours = (julianday(excluded.last_seen) - julianday(first_seen)) * 24`,
+		repo, number, baseBranch, seenAt, seenAt,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert pr %s#%d: %w", repo, number, err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) KnownPRs(ctx context.Context) (map[string]PRRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT repo, number, base_branch, first_seen, last_seen, age_hours FROM pull_requests`)
+	if err != nil {
+		return nil, fmt.Errorf("query known prs: %w", err)
+	}
+	defer rows.Close()
+
+	known := make(map[string]PRRecord)
+	for rows.Next() {
+		var rec PRRecord
+		if err := rows.Scan(&rec.Repo, &rec.Number, &rec.BaseBranch, &rec.FirstSeen, &rec.LastSeen, &rec.AgeHours); err != nil {
+			return nil, fmt.Errorf("scan pr record: %w", err)
+		}
+		known[prKey(rec.Repo, rec.Number)] = rec
+	}
+	return known, rows.Err()
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+func prKey(repo string, number int) string {
+	return fmt.Sprintf("%s#%d", repo, number)
+}